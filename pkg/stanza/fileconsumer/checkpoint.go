@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"time"
+)
+
+// Checkpoint is the durable record of a single file's read progress, keyed by its fingerprint.
+type Checkpoint struct {
+	FingerprintHash string
+	Offset          int64
+	Path            string
+	LastSeenAt      time.Time
+}
+
+// Checkpointer persists and restores (fingerprint, offset) pairs so that a Manager can resume
+// tailing exactly where it left off after a collector restart, instead of relying on `start_at` to
+// re-discover files from scratch. Implementations must be safe for concurrent use.
+type Checkpointer interface {
+	// Load returns the checkpoint stored for fingerprintHash, or nil if none exists.
+	Load(ctx context.Context, fingerprintHash string) (*Checkpoint, error)
+	// Save durably records cp, overwriting any previous entry for the same fingerprint.
+	Save(ctx context.Context, cp *Checkpoint) error
+	// Delete removes the checkpoint for fingerprintHash, if one exists.
+	Delete(ctx context.Context, fingerprintHash string) error
+	// List returns every checkpoint currently stored. Manager calls this once on startup to
+	// rehydrate knownFiles before the first poll.
+	List(ctx context.Context) ([]*Checkpoint, error)
+	// GC deletes checkpoints whose LastSeenAt is older than olderThan, so that entries for
+	// short-lived filenames don't accumulate forever.
+	GC(ctx context.Context, olderThan time.Time) error
+	// Close releases any resources held by the Checkpointer.
+	Close() error
+}
+
+// nopCheckpointer is a Checkpointer that persists nothing. It's the default when `storage` is
+// unset, preserving the historical in-memory-only behavior.
+type nopCheckpointer struct{}
+
+// newNopCheckpointer returns a Checkpointer that never persists anything.
+func newNopCheckpointer() Checkpointer {
+	return nopCheckpointer{}
+}
+
+func (nopCheckpointer) Load(context.Context, string) (*Checkpoint, error) { return nil, nil }
+func (nopCheckpointer) Save(context.Context, *Checkpoint) error { return nil }
+func (nopCheckpointer) Delete(context.Context, string) error { return nil }
+func (nopCheckpointer) List(context.Context) ([]*Checkpoint, error) { return nil, nil }
+func (nopCheckpointer) GC(context.Context, time.Time) error { return nil }
+func (nopCheckpointer) Close() error { return nil }
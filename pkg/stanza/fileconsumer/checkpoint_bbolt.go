@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var checkpointBucket = []byte("checkpoints")
+
+// bboltCheckpointer is a Checkpointer backed by a local BoltDB file, keyed by fingerprint hash.
+// It's used when `storage.path` is set without a collector storage extension, and needs no
+// cooperating extension component to be useful in a standalone deployment.
+type bboltCheckpointer struct {
+	db *bolt.DB
+}
+
+// newBboltCheckpointer opens (creating if necessary) a bbolt database at path. When sync is true,
+// every write transaction is fsynced before it returns, trading throughput for the guarantee that
+// a Save acknowledged to the caller survives a crash.
+func newBboltCheckpointer(path string, sync bool) (Checkpointer, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{NoSync: !sync})
+	if err != nil {
+		return nil, fmt.Errorf("open checkpoint db: %w", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(checkpointBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create checkpoint bucket: %w", err)
+	}
+	return &bboltCheckpointer{db: db}, nil
+}
+
+func (b *bboltCheckpointer) Load(_ context.Context, fingerprintHash string) (*Checkpoint, error) {
+	var cp *Checkpoint
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(checkpointBucket).Get([]byte(fingerprintHash))
+		if data == nil {
+			return nil
+		}
+		cp = &Checkpoint{}
+		return json.Unmarshal(data, cp)
+	})
+	return cp, err
+}
+
+func (b *bboltCheckpointer) Save(_ context.Context, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	// bbolt commits a write transaction atomically, so a process killed mid-Save leaves either
+	// the old value or the new one, never a torn write.
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointBucket).Put([]byte(cp.FingerprintHash), data)
+	})
+}
+
+func (b *bboltCheckpointer) Delete(_ context.Context, fingerprintHash string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointBucket).Delete([]byte(fingerprintHash))
+	})
+}
+
+func (b *bboltCheckpointer) List(_ context.Context) ([]*Checkpoint, error) {
+	var checkpoints []*Checkpoint
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointBucket).ForEach(func(_, data []byte) error {
+			cp := &Checkpoint{}
+			if err := json.Unmarshal(data, cp); err != nil {
+				return err
+			}
+			checkpoints = append(checkpoints, cp)
+			return nil
+		})
+	})
+	return checkpoints, err
+}
+
+func (b *bboltCheckpointer) GC(_ context.Context, olderThan time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(checkpointBucket)
+		var stale [][]byte
+		err := bucket.ForEach(func(key, data []byte) error {
+			cp := &Checkpoint{}
+			if err := json.Unmarshal(data, cp); err != nil {
+				return err
+			}
+			if cp.LastSeenAt.Before(olderThan) {
+				stale = append(stale, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range stale {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *bboltCheckpointer) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	checkpointKeyPrefix = "fileconsumer.checkpoint."
+	checkpointIndexKey  = "fileconsumer.checkpoint_index"
+)
+
+// persisterCheckpointer is a Checkpointer backed by the collector's storage extension, via the
+// same helper.Persister other stanza operators use for their own durable state. It's wired in at
+// Start time, once the operator's persister is available, superseding whichever Checkpointer
+// Config.buildManager chose by default.
+//
+// storage.Client, and so helper.Persister, exposes no way to enumerate keys, so persisterCheckpointer
+// keeps its own index of fingerprint hashes under checkpointIndexKey, updated alongside every
+// Save/Delete, so that List can still rehydrate knownFiles on startup.
+type persisterCheckpointer struct {
+	persister helper.Persister
+
+	mu sync.Mutex
+}
+
+// newPersisterCheckpointer adapts persister into a Checkpointer.
+func newPersisterCheckpointer(persister helper.Persister) Checkpointer {
+	return &persisterCheckpointer{persister: persister}
+}
+
+func (p *persisterCheckpointer) Load(ctx context.Context, fingerprintHash string) (*Checkpoint, error) {
+	data, err := p.persister.Get(ctx, checkpointKeyPrefix+fingerprintHash)
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+func (p *persisterCheckpointer) Save(ctx context.Context, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	if err := p.persister.Set(ctx, checkpointKeyPrefix+cp.FingerprintHash, data); err != nil {
+		return fmt.Errorf("set checkpoint: %w", err)
+	}
+	return p.addToIndex(ctx, cp.FingerprintHash)
+}
+
+func (p *persisterCheckpointer) Delete(ctx context.Context, fingerprintHash string) error {
+	if err := p.persister.Delete(ctx, checkpointKeyPrefix+fingerprintHash); err != nil {
+		return fmt.Errorf("delete checkpoint: %w", err)
+	}
+	return p.removeFromIndex(ctx, fingerprintHash)
+}
+
+// List loads the index of fingerprint hashes and returns every checkpoint it still points to.
+func (p *persisterCheckpointer) List(ctx context.Context) ([]*Checkpoint, error) {
+	p.mu.Lock()
+	index, err := p.loadIndex(ctx)
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*Checkpoint, 0, len(index))
+	for _, hash := range index {
+		cp, err := p.Load(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if cp != nil {
+			checkpoints = append(checkpoints, cp)
+		}
+	}
+	return checkpoints, nil
+}
+
+func (p *persisterCheckpointer) GC(ctx context.Context, olderThan time.Time) error {
+	checkpoints, err := p.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cp := range checkpoints {
+		if cp.LastSeenAt.Before(olderThan) {
+			if err := p.Delete(ctx, cp.FingerprintHash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *persisterCheckpointer) Close() error {
+	return nil
+}
+
+func (p *persisterCheckpointer) loadIndex(ctx context.Context) ([]string, error) {
+	data, err := p.persister.Get(ctx, checkpointIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint index: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var index []string
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint index: %w", err)
+	}
+	return index, nil
+}
+
+func (p *persisterCheckpointer) saveIndex(ctx context.Context, index []string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint index: %w", err)
+	}
+	if err := p.persister.Set(ctx, checkpointIndexKey, data); err != nil {
+		return fmt.Errorf("set checkpoint index: %w", err)
+	}
+	return nil
+}
+
+func (p *persisterCheckpointer) addToIndex(ctx context.Context, hash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index, err := p.loadIndex(ctx)
+	if err != nil {
+		return err
+	}
+	for _, existing := range index {
+		if existing == hash {
+			return nil
+		}
+	}
+	return p.saveIndex(ctx, append(index, hash))
+}
+
+func (p *persisterCheckpointer) removeFromIndex(ctx context.Context, hash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index, err := p.loadIndex(ctx)
+	if err != nil {
+		return err
+	}
+	filtered := index[:0]
+	for _, existing := range index {
+		if existing != hash {
+			filtered = append(filtered, existing)
+		}
+	}
+	return p.saveIndex(ctx, filtered)
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBboltCheckpointerCrashRecovery verifies that a checkpoint saved before a bbolt database is
+// closed is still readable after it's reopened at the same path, simulating a collector restart.
+func TestBboltCheckpointerCrashRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+	ctx := context.Background()
+
+	cp1, err := newBboltCheckpointer(path, true)
+	require.NoError(t, err)
+
+	want := &Checkpoint{
+		FingerprintHash: "abc123",
+		Offset:          4096,
+		Path:            "/var/log/app.log",
+		LastSeenAt:      time.Now().Truncate(time.Second),
+	}
+	require.NoError(t, cp1.Save(ctx, want))
+	require.NoError(t, cp1.Close())
+
+	cp2, err := newBboltCheckpointer(path, true)
+	require.NoError(t, err)
+	defer cp2.Close()
+
+	got, err := cp2.Load(ctx, "abc123")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, want.Offset, got.Offset)
+	require.Equal(t, want.Path, got.Path)
+	require.True(t, want.LastSeenAt.Equal(got.LastSeenAt))
+}
+
+// TestBboltCheckpointerDistinctFingerprints verifies that two files whose fingerprint hashes
+// differ are stored and retrieved independently, with neither clobbering the other.
+func TestBboltCheckpointerDistinctFingerprints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+	ctx := context.Background()
+
+	checkpointer, err := newBboltCheckpointer(path, false)
+	require.NoError(t, err)
+	defer checkpointer.Close()
+
+	a := &Checkpoint{FingerprintHash: "hash-a", Offset: 10, Path: "/var/log/a.log", LastSeenAt: time.Now()}
+	b := &Checkpoint{FingerprintHash: "hash-b", Offset: 20, Path: "/var/log/b.log", LastSeenAt: time.Now()}
+	require.NoError(t, checkpointer.Save(ctx, a))
+	require.NoError(t, checkpointer.Save(ctx, b))
+
+	gotA, err := checkpointer.Load(ctx, "hash-a")
+	require.NoError(t, err)
+	gotB, err := checkpointer.Load(ctx, "hash-b")
+	require.NoError(t, err)
+
+	require.Equal(t, int64(10), gotA.Offset)
+	require.Equal(t, "/var/log/a.log", gotA.Path)
+	require.Equal(t, int64(20), gotB.Offset)
+	require.Equal(t, "/var/log/b.log", gotB.Path)
+
+	all, err := checkpointer.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
+
+func TestBboltCheckpointerGC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+	ctx := context.Background()
+
+	checkpointer, err := newBboltCheckpointer(path, false)
+	require.NoError(t, err)
+	defer checkpointer.Close()
+
+	stale := &Checkpoint{FingerprintHash: "stale", Path: "/var/log/stale.log", LastSeenAt: time.Now().Add(-2 * time.Hour)}
+	fresh := &Checkpoint{FingerprintHash: "fresh", Path: "/var/log/fresh.log", LastSeenAt: time.Now()}
+	require.NoError(t, checkpointer.Save(ctx, stale))
+	require.NoError(t, checkpointer.Save(ctx, fresh))
+
+	require.NoError(t, checkpointer.GC(ctx, time.Now().Add(-time.Hour)))
+
+	all, err := checkpointer.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, "fresh", all[0].FingerprintHash)
+}
+
+// fakePersister is a minimal in-memory helper.Persister for exercising persisterCheckpointer
+// without a running storage extension.
+type fakePersister struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newFakePersister() *fakePersister {
+	return &fakePersister{data: make(map[string][]byte)}
+}
+
+func (f *fakePersister) Get(_ context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data[key], nil
+}
+
+func (f *fakePersister) Set(_ context.Context, key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakePersister) Delete(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+// TestPersisterCheckpointerListUsesIndex verifies that List can enumerate checkpoints even though
+// the underlying helper.Persister has no native key enumeration, by way of persisterCheckpointer's
+// own index key.
+func TestPersisterCheckpointerListUsesIndex(t *testing.T) {
+	ctx := context.Background()
+	checkpointer := newPersisterCheckpointer(newFakePersister())
+
+	require.NoError(t, checkpointer.Save(ctx, &Checkpoint{FingerprintHash: "hash-a", Path: "/a.log"}))
+	require.NoError(t, checkpointer.Save(ctx, &Checkpoint{FingerprintHash: "hash-b", Path: "/b.log"}))
+
+	all, err := checkpointer.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	require.NoError(t, checkpointer.Delete(ctx, "hash-a"))
+	all, err = checkpointer.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, "hash-b", all[0].FingerprintHash)
+}
@@ -30,6 +30,8 @@ const (
 	defaultMaxLogSize         = 1024 * 1024
 	defaultMaxConcurrentFiles = 1024
 	allowFileDeletion         = "filelog.allowFileDeletion"
+	defaultFlushInterval      = time.Second
+	defaultCheckpointTTL      = 72 * time.Hour
 )
 
 func init() {
@@ -54,9 +56,31 @@ func NewConfig() *Config {
 		FingerprintSize:         DefaultFingerprintSize,
 		MaxLogSize:              defaultMaxLogSize,
 		MaxConcurrentFiles:      defaultMaxConcurrentFiles,
+		Decompression:           DecompressionNone,
+		Storage: StorageConfig{
+			FlushInterval: defaultFlushInterval,
+			TTL:           defaultCheckpointTTL,
+		},
 	}
 }
 
+// StorageConfig configures how file offsets and fingerprints are checkpointed, so that a collector
+// restart can resume tailing without re-reading `start_at` or duplicating already-emitted logs.
+type StorageConfig struct {
+	// Path, if set, enables checkpointing to a local bbolt file at this location. Ignored when a
+	// storage extension is configured on the receiver, which takes precedence.
+	Path string `mapstructure:"path,omitempty"`
+	// FlushInterval controls how often in-progress offsets are checkpointed while a file is being
+	// tailed, rather than only once a file is fully read.
+	FlushInterval time.Duration `mapstructure:"flush_interval,omitempty"`
+	// Sync fsyncs every checkpoint write before it's considered durable, trading throughput for
+	// the guarantee that an acknowledged checkpoint survives a crash.
+	Sync bool `mapstructure:"sync,omitempty"`
+	// TTL bounds how long a checkpoint for a file that's no longer being seen is kept around,
+	// so the store doesn't grow unbounded on short-lived filenames.
+	TTL time.Duration `mapstructure:"ttl,omitempty"`
+}
+
 // Config is the configuration of a file input operator
 type Config struct {
 	Finder                  `mapstructure:",squash"`
@@ -71,6 +95,8 @@ type Config struct {
 	MaxConcurrentFiles      int                   `mapstructure:"max_concurrent_files,omitempty"`
 	DeleteAfterRead         bool                  `mapstructure:"delete_after_read,omitempty"`
 	Splitter                helper.SplitterConfig `mapstructure:",squash,omitempty"`
+	Decompression           Decompression         `mapstructure:"decompression,omitempty"`
+	Storage                 StorageConfig         `mapstructure:"storage,omitempty"`
 }
 
 // Build will build a file input operator from the supplied configuration
@@ -124,6 +150,12 @@ func (c Config) buildManager(logger *zap.SugaredLogger, emit EmitFunc, factory s
 	default:
 		return nil, fmt.Errorf("invalid start_at location '%s'", c.StartAt)
 	}
+
+	checkpointer, err := c.buildCheckpointer()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Manager{
 		SugaredLogger: logger.With("component", "fileconsumer"),
 		cancel:        func() {},
@@ -133,6 +165,7 @@ func (c Config) buildManager(logger *zap.SugaredLogger, emit EmitFunc, factory s
 				fingerprintSize: int(c.FingerprintSize),
 				maxLogSize:      int(c.MaxLogSize),
 				emit:            emit,
+				decompression:   c.Decompression,
 			},
 			fromBeginning:   startAtBeginning,
 			splitterFactory: factory,
@@ -143,11 +176,24 @@ func (c Config) buildManager(logger *zap.SugaredLogger, emit EmitFunc, factory s
 		pollInterval:    c.PollInterval,
 		maxBatchFiles:   c.MaxConcurrentFiles / 2,
 		deleteAfterRead: c.DeleteAfterRead,
+		checkpointer:    checkpointer,
+		checkpointTTL:   c.Storage.TTL,
+		flushInterval:   c.Storage.FlushInterval,
 		knownFiles:      make([]*Reader, 0, 10),
 		seenPaths:       make(map[string]struct{}, 100),
 	}, nil
 }
 
+// buildCheckpointer builds the default Checkpointer implied by c.Storage. A storage extension,
+// when the receiver is given one at Start time, takes precedence over this choice; see
+// newPersisterCheckpointer.
+func (c Config) buildCheckpointer() (Checkpointer, error) {
+	if c.Storage.Path == "" {
+		return newNopCheckpointer(), nil
+	}
+	return newBboltCheckpointer(c.Storage.Path, c.Storage.Sync)
+}
+
 func (c Config) validate() error {
 	if len(c.Include) == 0 {
 		return fmt.Errorf("required argument `include` is empty")
@@ -185,6 +231,26 @@ func (c Config) validate() error {
 		return fmt.Errorf("`delete_after_read` cannot be used with `start_at: end`")
 	}
 
+	switch c.Decompression {
+	case "", DecompressionNone, DecompressionAuto, DecompressionGzip, DecompressionZstd:
+	default:
+		return fmt.Errorf("invalid decompression '%s'", c.Decompression)
+	}
+
+	// Compressed streams can't be seeked back into reliably, so they're always read from the
+	// start whatever start_at says; `start_at: end` is rejected rather than silently ignored.
+	if c.Decompression != "" && c.Decompression != DecompressionNone && c.StartAt == "end" {
+		return fmt.Errorf("`decompression` cannot be used with `start_at: end`")
+	}
+
+	if c.Storage.Path != "" && c.Storage.FlushInterval <= 0 {
+		return fmt.Errorf("`storage.flush_interval` must be positive when `storage.path` is set")
+	}
+
+	if c.Storage.TTL < 0 {
+		return fmt.Errorf("`storage.ttl` must not be negative")
+	}
+
 	_, err := c.Splitter.EncodingConfig.Build()
 	if err != nil {
 		return err
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Decompression identifies the compression format a matched file is expected to be encoded with.
+type Decompression string
+
+const (
+	// DecompressionNone reads matched files as plain text. This is the default.
+	DecompressionNone Decompression = "none"
+	// DecompressionAuto detects gzip and zstd files by extension and magic bytes.
+	DecompressionAuto Decompression = "auto"
+	// DecompressionGzip treats every matched file as gzip-compressed.
+	DecompressionGzip Decompression = "gzip"
+	// DecompressionZstd treats every matched file as zstd-compressed.
+	DecompressionZstd Decompression = "zstd"
+)
+
+// gzipMagic and zstdMagic are the fixed byte sequences each format begins a stream with, used by
+// DecompressionAuto when a file's extension alone isn't conclusive (e.g. a rotated `app.log.1`
+// that a log rotator has renamed without re-compressing, versus one that has).
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// detectDecompression resolves the configured Decompression setting against a file's path and the
+// first bytes read from it, returning the concrete format to decode the file with.
+func detectDecompression(setting Decompression, path string, header []byte) (Decompression, error) {
+	switch setting {
+	case "", DecompressionNone:
+		return DecompressionNone, nil
+	case DecompressionGzip, DecompressionZstd:
+		return setting, nil
+	case DecompressionAuto:
+		switch {
+		case bytes.HasPrefix(header, gzipMagic) || strings.HasSuffix(path, ".gz"):
+			return DecompressionGzip, nil
+		case bytes.HasPrefix(header, zstdMagic) || strings.HasSuffix(path, ".zst"):
+			return DecompressionZstd, nil
+		default:
+			return DecompressionNone, nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported decompression '%s'", setting)
+	}
+}
+
+// newDecompressionReader wraps r in a decompressing reader for the given format. The returned
+// reader is meant to be consumed once, start to finish, rather than tailed incrementally like an
+// uncompressed file: none of the supported formats can be seeked into reliably once a new record
+// has been appended upstream, so a compressed file is always read in full and then, if
+// `delete_after_read` is set, removed.
+func newDecompressionReader(format Decompression, r io.Reader) (io.Reader, error) {
+	switch format {
+	case DecompressionGzip:
+		return gzip.NewReader(r)
+	case DecompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return r, nil
+	}
+}
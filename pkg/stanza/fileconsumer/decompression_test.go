@@ -0,0 +1,209 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// lineSplitterFactory is a minimal splitterFactory for tests that don't exercise encoding or
+// multiline behavior.
+type lineSplitterFactory struct{}
+
+func (lineSplitterFactory) Build(int) (bufio.SplitFunc, error) { return bufio.ScanLines, nil }
+
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDetectDecompression(t *testing.T) {
+	gz := gzipBytes(t, "hello\n")
+	zs := zstdBytes(t, "hello\n")
+
+	cases := []struct {
+		name     string
+		setting  Decompression
+		path     string
+		header   []byte
+		expected Decompression
+	}{
+		{"none setting", DecompressionNone, "app.log", gz[:4], DecompressionNone},
+		{"explicit gzip", DecompressionGzip, "app.log", nil, DecompressionGzip},
+		{"explicit zstd", DecompressionZstd, "app.log", nil, DecompressionZstd},
+		{"auto by gzip magic", DecompressionAuto, "app.log.1", gz[:4], DecompressionGzip},
+		{"auto by zstd magic", DecompressionAuto, "app.log.1", zs[:4], DecompressionZstd},
+		{"auto by gz extension", DecompressionAuto, "app.log.1.gz", nil, DecompressionGzip},
+		{"auto by zst extension", DecompressionAuto, "app.log.1.zst", nil, DecompressionZstd},
+		{"auto plain text", DecompressionAuto, "app.log", []byte("2023-01-01"), DecompressionNone},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := detectDecompression(tc.setting, tc.path, tc.header)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestNewDecompressionReaderRoundTrip(t *testing.T) {
+	content := "line one\nline two\n"
+
+	gzReader, err := newDecompressionReader(DecompressionGzip, bytes.NewReader(gzipBytes(t, content)))
+	require.NoError(t, err)
+	gzOut, err := io.ReadAll(gzReader)
+	require.NoError(t, err)
+	require.Equal(t, content, string(gzOut))
+
+	zstdReader, err := newDecompressionReader(DecompressionZstd, bytes.NewReader(zstdBytes(t, content)))
+	require.NoError(t, err)
+	zstdOut, err := io.ReadAll(zstdReader)
+	require.NoError(t, err)
+	require.Equal(t, content, string(zstdOut))
+}
+
+// TestReaderDecompressesGzipCorpus verifies that a Reader built over a gzip-compressed file
+// decompresses it, emits every line, fingerprints the decompressed bytes (not the gzip header, so
+// that `app.log.1.gz` has the same fingerprint as its content uncompressed), and marks itself
+// done after a single pass.
+func TestReaderDecompressesGzipCorpus(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log.1.gz")
+	content := "first line\nsecond line\nthird line\n"
+	require.NoError(t, os.WriteFile(path, gzipBytes(t, content), 0o600))
+
+	var mu sync.Mutex
+	var got []string
+	emit := func(_ context.Context, token []byte, _ map[string]any) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, string(token))
+	}
+
+	factory := newTestReaderFactory(t, DecompressionAuto, emit)
+	reader, err := factory.newReader(path, nil)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	require.NoError(t, reader.ReadToEnd(context.Background()))
+	require.Equal(t, []string{"first line", "second line", "third line"}, got)
+	require.True(t, reader.eof)
+
+	plainFingerprint := NewFingerprint([]byte("first line\nsecond line\nthird line\n")[:reader.fingerprintSize])
+	require.Equal(t, plainFingerprint.Hash(), reader.Fingerprint.Hash())
+
+	// A second pass is a no-op: the stream was already fully consumed.
+	got = nil
+	require.NoError(t, reader.ReadToEnd(context.Background()))
+	require.Empty(t, got)
+}
+
+func TestReaderDecompressesZstdCorpus(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log.1.zst")
+	content := "alpha\nbeta\n"
+	require.NoError(t, os.WriteFile(path, zstdBytes(t, content), 0o600))
+
+	var got []string
+	emit := func(_ context.Context, token []byte, _ map[string]any) {
+		got = append(got, string(token))
+	}
+
+	factory := newTestReaderFactory(t, DecompressionAuto, emit)
+	reader, err := factory.newReader(path, nil)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	require.NoError(t, reader.ReadToEnd(context.Background()))
+	require.Equal(t, []string{"alpha", "beta"}, got)
+	require.True(t, reader.eof)
+}
+
+// TestManagerDeletesCompressedFileAfterRead verifies that Manager.poll itself removes a fully-read
+// compressed file, and its checkpoint, once delete_after_read is set, rather than leaving it around
+// to tail (which it can't be).
+func TestManagerDeletesCompressedFileAfterRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log.gz")
+	require.NoError(t, os.WriteFile(path, gzipBytes(t, "only line\n"), 0o600))
+
+	cfg := NewConfig()
+	cfg.Include = []string{path}
+	cfg.StartAt = "beginning"
+	cfg.Decompression = DecompressionGzip
+	cfg.DeleteAfterRead = true
+	cfg.Storage.Path = filepath.Join(dir, "checkpoints.db")
+
+	var got []string
+	emit := func(_ context.Context, token []byte, _ map[string]any) {
+		got = append(got, string(token))
+	}
+
+	m, err := cfg.buildManager(zap.NewNop().Sugar(), emit, lineSplitterFactory{})
+	require.NoError(t, err)
+
+	m.poll(context.Background())
+	require.Equal(t, []string{"only line"}, got)
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+
+	cp, err := m.checkpointer.Load(context.Background(), NewFingerprint([]byte("only line\n")).Hash())
+	require.NoError(t, err)
+	require.Nil(t, cp)
+}
+
+func newTestReaderFactory(t *testing.T, decompression Decompression, emit EmitFunc) *readerFactory {
+	t.Helper()
+	return &readerFactory{
+		SugaredLogger: zap.NewNop().Sugar(),
+		readerConfig: &readerConfig{
+			fingerprintSize: 10,
+			maxLogSize:      defaultMaxLogSize,
+			emit:            emit,
+			decompression:   decompression,
+		},
+		fromBeginning:   true,
+		splitterFactory: lineSplitterFactory{},
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Finder locates the files on disk that match a set of include/exclude globs.
+type Finder struct {
+	Include []string `mapstructure:"include,omitempty"`
+	Exclude []string `mapstructure:"exclude,omitempty"`
+}
+
+// FindFiles returns the paths currently matching Include, minus any matching Exclude.
+func (f Finder) FindFiles() []string {
+	all := make(map[string]struct{})
+	for _, include := range f.Include {
+		matches, _ := doublestar.FilepathGlob(include)
+	matchLoop:
+		for _, match := range matches {
+			for _, exclude := range f.Exclude {
+				if ok, _ := doublestar.PathMatch(exclude, match); ok {
+					continue matchLoop
+				}
+			}
+			all[match] = struct{}{}
+		}
+	}
+
+	paths := make([]string, 0, len(all))
+	for path := range all {
+		paths = append(paths, path)
+	}
+	return paths
+}
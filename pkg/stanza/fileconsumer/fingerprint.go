@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	// DefaultFingerprintSize is the default number of bytes read to identify a file.
+	DefaultFingerprintSize helper.ByteSize = 1000
+	// MinFingerprintSize is the fewest bytes allowed for a fingerprint, below which two
+	// different files could too easily be mistaken for one another.
+	MinFingerprintSize helper.ByteSize = 16
+)
+
+// Fingerprint identifies a file by the first bytes of its content, so that a file that's been
+// rotated or renamed between polls can still be recognized as the same file. For a file matched
+// under Config.Decompression, the fingerprint is taken over the first bytes of the *decompressed*
+// stream, so a compressed file (e.g. `app.log.1.gz`) is identified by its content, not by its
+// compression header, and fingerprints the same as that content would uncompressed.
+type Fingerprint struct {
+	FirstBytes []byte
+}
+
+// NewFingerprint returns a Fingerprint over a copy of first.
+func NewFingerprint(first []byte) *Fingerprint {
+	return &Fingerprint{FirstBytes: append([]byte(nil), first...)}
+}
+
+// StartsWith returns true if old's bytes are a prefix of f's, meaning f could be a continuation of
+// the same file old was read from.
+func (f *Fingerprint) StartsWith(old *Fingerprint) bool {
+	if old == nil || len(old.FirstBytes) == 0 {
+		return false
+	}
+	if len(old.FirstBytes) > len(f.FirstBytes) {
+		return false
+	}
+	return bytes.Equal(old.FirstBytes, f.FirstBytes[:len(old.FirstBytes)])
+}
+
+// Hash returns a stable, fixed-size identifier for the fingerprint, suitable for use as a
+// Checkpointer key.
+func (f *Fingerprint) Hash() string {
+	sum := sha256.Sum256(f.FirstBytes)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// Manager polls the filesystem for files matching Config.Include, tails each one, and persists
+// enough state via its Checkpointer to resume after a restart without re-reading start_at or
+// duplicating already-emitted logs.
+type Manager struct {
+	*zap.SugaredLogger
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	readerFactory readerFactory
+	finder        Finder
+	roller        roller
+
+	pollInterval    time.Duration
+	maxBatchFiles   int
+	deleteAfterRead bool
+
+	checkpointer  Checkpointer
+	checkpointTTL time.Duration
+
+	// flushInterval throttles how often poll persists in-progress offsets; a file that's fully
+	// read or deleted is always checkpointed immediately regardless of this interval. Zero means
+	// every poll flushes.
+	flushInterval time.Duration
+	lastFlush     time.Time
+
+	knownFiles []*Reader
+	seenPaths  map[string]struct{}
+}
+
+// Start begins polling for files on a background goroutine. If persister is non-nil, it's wrapped
+// as a Checkpointer backed by the collector's storage extension, overriding whichever default
+// (bbolt or no-op) Config.buildManager chose; this mirrors how other stanza operators treat an
+// injected Persister as authoritative over their own config-derived defaults.
+func (m *Manager) Start(persister helper.Persister) error {
+	if persister != nil {
+		next := newPersisterCheckpointer(persister)
+		if m.checkpointer != nil && m.checkpointer != next {
+			if err := m.checkpointer.Close(); err != nil {
+				m.Errorw("Closing previous checkpointer", "error", err)
+			}
+		}
+		m.checkpointer = next
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	if err := m.rehydrate(ctx); err != nil {
+		m.Errorw("Rehydrating checkpoints, starting from start_at instead", "error", err)
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop cancels polling and waits for the background goroutine to exit, then flushes every known
+// file's current offset unconditionally, ignoring flushInterval, so that shutting down doesn't
+// lose progress made since the last throttled flush, and closes the Checkpointer.
+func (m *Manager) Stop() error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+
+	ctx := context.Background()
+	for _, reader := range m.knownFiles {
+		// A compressed reader's Offset isn't meaningful progress into the file; its checkpoint
+		// is the compressedDoneOffset sentinel instead, already saved by poll once it hit eof.
+		if reader.decompression == DecompressionNone {
+			if err := m.saveCheckpoint(ctx, reader, reader.Offset); err != nil {
+				m.Errorw("Saving checkpoint", "path", reader.path, "error", err)
+			}
+		}
+		reader.Close()
+	}
+	return m.checkpointer.Close()
+}
+
+// rehydrate restores knownFiles from the Checkpointer before the first poll runs, so that tailing
+// resumes exactly where it left off, even across a collector restart.
+func (m *Manager) rehydrate(ctx context.Context) error {
+	checkpoints, err := m.checkpointer.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	readers := make([]*Reader, 0, len(checkpoints))
+	for _, cp := range checkpoints {
+		if _, err := os.Stat(cp.Path); err != nil {
+			// The file is gone; nothing to rehydrate, and poll will naturally drop its
+			// checkpoint once its TTL elapses.
+			continue
+		}
+		reader, err := m.readerFactory.newReader(cp.Path, cp)
+		if err != nil {
+			m.Errorw("Rehydrating checkpoint", "path", cp.Path, "error", err)
+			continue
+		}
+		readers = append(readers, reader)
+		m.seenPaths[cp.Path] = struct{}{}
+	}
+	m.knownFiles = readers
+	m.roller.roll(readers)
+	return nil
+}
+
+// poll runs one discovery/read/checkpoint cycle.
+func (m *Manager) poll(ctx context.Context) {
+	paths := m.finder.FindFiles()
+	if len(paths) > m.maxBatchFiles {
+		paths = paths[:m.maxBatchFiles]
+	}
+
+	existing := make(map[string]*Reader, len(m.knownFiles))
+	for _, reader := range m.knownFiles {
+		existing[reader.path] = reader
+	}
+
+	flush := m.flushInterval <= 0 || time.Since(m.lastFlush) >= m.flushInterval
+
+	active := make([]*Reader, 0, len(paths))
+	for _, path := range paths {
+		reader, ok := existing[path]
+		if !ok {
+			var err error
+			reader, err = m.readerFactory.newReader(path, nil)
+			if err != nil {
+				m.Errorw("Opening file", "path", path, "error", err)
+				continue
+			}
+		}
+		m.seenPaths[path] = struct{}{}
+
+		if err := reader.ReadToEnd(ctx); err != nil {
+			m.Errorw("Reading file", "path", path, "error", err)
+		}
+
+		switch {
+		case reader.eof && reader.decompression != DecompressionNone && m.deleteAfterRead:
+			// The whole decompressed stream has been emitted and there's nowhere left to tail
+			// it from, so the file (and any checkpoint for it) can go now rather than waiting
+			// for the roller to notice it's disappeared.
+			if err := os.Remove(path); err != nil {
+				m.Errorw("Deleting file after read", "path", path, "error", err)
+			}
+			if err := m.checkpointer.Delete(ctx, reader.Fingerprint.Hash()); err != nil {
+				m.Errorw("Deleting checkpoint", "path", path, "error", err)
+			}
+			continue
+		case reader.eof && reader.decompression != DecompressionNone:
+			// delete_after_read is off, so the file stays put; record that it's fully consumed
+			// so a restart before it's rotated away doesn't re-decompress and re-emit it.
+			if err := m.saveCheckpoint(ctx, reader, compressedDoneOffset); err != nil {
+				m.Errorw("Saving checkpoint", "path", path, "error", err)
+			}
+		default:
+			if flush {
+				if err := m.saveCheckpoint(ctx, reader, reader.Offset); err != nil {
+					m.Errorw("Saving checkpoint", "path", path, "error", err)
+				}
+			}
+		}
+
+		active = append(active, reader)
+	}
+
+	if flush {
+		m.lastFlush = time.Now()
+	}
+
+	m.roller.readLostFiles(ctx, active)
+	m.roller.roll(active)
+	m.knownFiles = active
+
+	if m.checkpointTTL > 0 {
+		if err := m.checkpointer.GC(ctx, time.Now().Add(-m.checkpointTTL)); err != nil {
+			m.Errorw("Garbage collecting checkpoints", "error", err)
+		}
+	}
+}
+
+func (m *Manager) saveCheckpoint(ctx context.Context, reader *Reader, offset int64) error {
+	return m.checkpointer.Save(ctx, &Checkpoint{
+		FingerprintHash: reader.Fingerprint.Hash(),
+		Offset:          offset,
+		Path:            reader.path,
+		LastSeenAt:      time.Now(),
+	})
+}
@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// sniffLen is how many leading bytes are read from a file to decide, under DecompressionAuto,
+// whether it's gzip- or zstd-compressed. It only needs to cover the longest magic number in use.
+const sniffLen = 4
+
+// compressedDoneOffset is the Checkpoint.Offset sentinel for a compressed file that's already
+// been read to EOF but, because delete_after_read isn't set, is still on disk. It lets rehydrate
+// recognize the file as fully processed instead of decompressing and re-emitting it from scratch.
+const compressedDoneOffset int64 = -1
+
+// readerConfig carries the options every Reader built by a readerFactory shares.
+type readerConfig struct {
+	fingerprintSize int
+	maxLogSize      int
+	emit            EmitFunc
+	decompression   Decompression
+}
+
+// readerFactory builds Readers for newly-discovered files and for files that already have a
+// Checkpoint to resume from.
+type readerFactory struct {
+	*zap.SugaredLogger
+	*readerConfig
+	fromBeginning   bool
+	splitterFactory splitterFactory
+	encodingConfig  helper.EncodingConfig
+}
+
+// newReader opens path, determines whether it's compressed, and returns a Reader positioned
+// according to fromBeginning (or at cp.Offset, if cp is non-nil and its fingerprint still matches).
+func (f *readerFactory) newReader(path string, cp *Checkpoint) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+
+	header := make([]byte, sniffLen)
+	n, _ := io.ReadFull(file, header)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("seek %s: %w", path, err)
+	}
+
+	decompression, err := detectDecompression(f.decompression, path, header[:n])
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// Fingerprinting consumes from a throwaway decompression stream over file; file itself is
+	// always left seeked back to 0 afterward, regardless of format.
+	fp, err := f.fingerprint(path, file, decompression)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	splitFunc, err := f.splitterFactory.Build(f.maxLogSize)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	r := &Reader{
+		SugaredLogger: f.SugaredLogger.With("path", path),
+		readerConfig:  f.readerConfig,
+		Fingerprint:   fp,
+		path:          path,
+		decompression: decompression,
+		splitFunc:     splitFunc,
+	}
+
+	if decompression != DecompressionNone {
+		// The fingerprinting pass above already consumed (and can't rewind) part of the
+		// decompressed stream, so the real read uses a fresh handle and decoder over the same
+		// file, started from byte zero.
+		_ = file.Close()
+
+		if cp != nil && fp.Hash() == cp.FingerprintHash && cp.Offset == compressedDoneOffset {
+			// Already decompressed and emitted in full before a restart, and never deleted
+			// (delete_after_read is off); there's nothing left to read.
+			r.eof = true
+			return r, nil
+		}
+
+		fresh, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("reopen %s: %w", path, err)
+		}
+		decoded, err := newDecompressionReader(decompression, fresh)
+		if err != nil {
+			_ = fresh.Close()
+			return nil, fmt.Errorf("open decompression reader for %s: %w", path, err)
+		}
+		r.file = fresh
+		r.decoded = decoded
+		return r, nil
+	}
+
+	switch {
+	case cp != nil && fp.Hash() == cp.FingerprintHash:
+		if _, err := file.Seek(cp.Offset, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("seek %s to checkpoint offset: %w", path, err)
+		}
+		r.Offset = cp.Offset
+	case f.fromBeginning:
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("seek %s to start: %w", path, err)
+		}
+	default:
+		end, err := file.Seek(0, io.SeekEnd)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("seek %s to end: %w", path, err)
+		}
+		r.Offset = end
+	}
+	r.file = file
+	r.decoded = file
+	return r, nil
+}
+
+// fingerprint reads the first fingerprintSize bytes of file's decompressed stream (identity, for
+// DecompressionNone) and leaves file seeked back to the start.
+func (f *readerFactory) fingerprint(path string, file *os.File, decompression Decompression) (*Fingerprint, error) {
+	decoded, err := newDecompressionReader(decompression, file)
+	if err != nil {
+		return nil, fmt.Errorf("open decompression reader for %s: %w", path, err)
+	}
+	buf := make([]byte, f.fingerprintSize)
+	n, _ := io.ReadFull(decoded, buf)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek %s back to start: %w", path, err)
+	}
+	return NewFingerprint(buf[:n]), nil
+}
+
+// Reader tails a single file, emitting one record per split token.
+type Reader struct {
+	*zap.SugaredLogger
+	*readerConfig
+
+	Fingerprint *Fingerprint
+	Offset      int64
+
+	path          string
+	file          *os.File
+	decoded       io.Reader
+	decompression Decompression
+	splitFunc     bufio.SplitFunc
+	eof           bool
+}
+
+// ReadToEnd reads every complete record currently available and passes each to emit. For an
+// uncompressed file this tails incrementally, remembering Offset for the next poll. For a
+// compressed file it always consumes the whole decompressed stream in one pass, since the format
+// can't be tailed, and latches eof so a later poll (before the file is deleted or rotated away)
+// is a no-op.
+func (r *Reader) ReadToEnd(ctx context.Context) error {
+	if r.eof {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r.decoded)
+	scanner.Buffer(make([]byte, 0, r.maxLogSize), r.maxLogSize)
+	// consumed counts the bytes the split func has advanced past, so that Offset ends at the last
+	// emitted record rather than wherever the scanner has buffered ahead to.
+	consumed := r.Offset
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := r.splitFunc(data, atEOF)
+		consumed += int64(advance)
+		return advance, token, err
+	})
+
+	for scanner.Scan() {
+		token := scanner.Bytes()
+		attrs := map[string]any{"log.file.path": r.path}
+		r.emit(ctx, append([]byte(nil), token...), attrs)
+		if r.decompression == DecompressionNone {
+			r.Offset = consumed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scan %s: %w", r.path, err)
+	}
+
+	if r.decompression != DecompressionNone {
+		r.eof = true
+		return nil
+	}
+	// Rewind past any partial record the scanner buffered, so the next poll reads it in full.
+	if _, err := r.file.Seek(r.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek %s to offset: %w", r.path, err)
+	}
+	return nil
+}
+
+// Close releases the file handle (and any decompressor wrapping it) held by the Reader.
+func (r *Reader) Close() {
+	if closer, ok := r.decoded.(io.Closer); ok && r.decoded != r.file {
+		_ = closer.Close()
+	}
+	if r.file != nil {
+		_ = r.file.Close()
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import "context"
+
+// roller remembers the readers of the previous poll so that any of them no longer matched by the
+// Finder gets one final read and is closed in the poll that finds it missing. A reader is not
+// kept for a later poll, and a file renamed between polls is not picked up under its new name.
+type roller struct {
+	previousPollFiles []*Reader
+}
+
+func newRoller() roller {
+	return roller{}
+}
+
+// readLostFiles gives any reader that was known last poll, but wasn't matched again this poll, one
+// final read in case a rotator left buffered data behind before the previous handle is closed.
+func (r *roller) readLostFiles(ctx context.Context, active []*Reader) {
+	isActive := make(map[*Reader]struct{}, len(active))
+	for _, reader := range active {
+		isActive[reader] = struct{}{}
+	}
+	for _, reader := range r.previousPollFiles {
+		if _, ok := isActive[reader]; ok {
+			continue
+		}
+		_ = reader.ReadToEnd(ctx)
+		reader.Close()
+	}
+}
+
+func (r *roller) roll(active []*Reader) {
+	r.previousPollFiles = active
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"bufio"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// splitterFactory builds the bufio.SplitFunc a Reader uses to break a file's decoded byte stream
+// into individual records.
+type splitterFactory interface {
+	Build(maxLogSize int) (bufio.SplitFunc, error)
+}
+
+// multilineSplitterFactory builds a split func from the encoding, flusher and multiline settings
+// of a Config's Splitter.
+type multilineSplitterFactory struct {
+	encodingConfig helper.EncodingConfig
+	flusher        helper.FlusherConfig
+	multiline      helper.MultilineConfig
+}
+
+func newMultilineSplitterFactory(
+	encodingConfig helper.EncodingConfig,
+	flusher helper.FlusherConfig,
+	multiline helper.MultilineConfig,
+) splitterFactory {
+	return &multilineSplitterFactory{encodingConfig: encodingConfig, flusher: flusher, multiline: multiline}
+}
+
+func (f *multilineSplitterFactory) Build(maxLogSize int) (bufio.SplitFunc, error) {
+	enc, err := f.encodingConfig.Build()
+	if err != nil {
+		return nil, err
+	}
+	splitFunc, err := f.multiline.Factory(enc, maxLogSize)
+	if err != nil {
+		return nil, err
+	}
+	return f.flusher.Factory(splitFunc), nil
+}
+
+// customizeSplitterFactory wraps a caller-supplied bufio.SplitFunc, applying only the flusher.
+type customizeSplitterFactory struct {
+	flusher   helper.FlusherConfig
+	splitFunc bufio.SplitFunc
+}
+
+func newCustomizeSplitterFactory(flusher helper.FlusherConfig, splitFunc bufio.SplitFunc) splitterFactory {
+	return &customizeSplitterFactory{flusher: flusher, splitFunc: splitFunc}
+}
+
+func (f *customizeSplitterFactory) Build(int) (bufio.SplitFunc, error) {
+	return f.flusher.Factory(f.splitFunc), nil
+}